@@ -0,0 +1,71 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-oracle-terraform/opc"
+)
+
+// Client is a thin wrapper around an *http.Client that carries the
+// credentials and endpoint needed to issue requests against an Oracle
+// Cloud service, plus the opc.Config it was built from.
+type Client struct {
+	IdentityDomain *string
+	UserName       *string
+	Password       *string
+	APIEndpoint    *url.URL
+
+	Config *opc.Config
+
+	httpClient *http.Client
+}
+
+// NewClient builds a Client from an opc.Config.
+func NewClient(c *opc.Config) (*Client, error) {
+	if c.APIEndpoint == nil {
+		return nil, fmt.Errorf("client: no APIEndpoint set in config")
+	}
+
+	return &Client{
+		IdentityDomain: c.IdentityDomain,
+		UserName:       c.Username,
+		Password:       c.Password,
+		APIEndpoint:    c.APIEndpoint,
+		Config:         c,
+		httpClient:     &http.Client{},
+	}, nil
+}
+
+// BuildNonJSONRequest builds a request against path, relative to the
+// client's APIEndpoint, without marshaling body into JSON.
+func (c *Client) BuildNonJSONRequest(method, path string, body io.ReadSeeker) (*http.Request, error) {
+	endpoint := *c.APIEndpoint
+	endpoint.Path = path
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = body
+	}
+
+	req, err := http.NewRequest(method, endpoint.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("client: building %s %s request: %s", method, path, err)
+	}
+	return req, nil
+}
+
+// ExecuteRequest issues req and returns the raw response, without
+// inspecting its status code.
+func (c *Client) ExecuteRequest(req *http.Request) (*http.Response, error) {
+	return c.httpClient.Do(req)
+}
+
+// DebugLogString logs msg through the standard logger with a [DEBUG] prefix;
+// it does no log-level check of its own.
+func (c *Client) DebugLogString(msg string) {
+	log.Printf("[DEBUG] %s", msg)
+}
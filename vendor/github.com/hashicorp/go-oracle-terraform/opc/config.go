@@ -0,0 +1,46 @@
+package opc
+
+import "net/url"
+
+// Config holds the credentials and endpoint information needed to
+// authenticate and issue requests against an Oracle Cloud service.
+type Config struct {
+	// Username and Password are the account credentials.
+	Username *string
+	Password *string
+
+	// IdentityDomain is the Oracle Cloud identity domain (tenant) the
+	// account belongs to.
+	IdentityDomain *string
+
+	// APIEndpoint is the base URL requests are issued against.
+	APIEndpoint *url.URL
+
+	// AuthVersion selects the identity backend used to authenticate:
+	// "v1" for the legacy Swift TempAuth flow, "v2" or "v3" for Keystone
+	// password authentication. Left empty, the backend is inferred from
+	// AuthURL.
+	AuthVersion string
+
+	// AuthURL is the identity endpoint used for Keystone v2/v3
+	// authentication, e.g. "https://identity.example.com/v2.0".
+	AuthURL string
+
+	// TenantName is the Keystone tenant (project) to scope the token to.
+	TenantName string
+
+	// DomainName is the Keystone v3 domain the user and project belong
+	// to.
+	DomainName string
+
+	// Region selects the service-catalog endpoint for a specific region.
+	// Left empty, or when no object-store endpoint in the catalog matches
+	// it, the first object-store endpoint in the catalog is used.
+	Region string
+
+	// MaxRetries bounds the number of retries issued for a throttled (429)
+	// or server-error (5xx) response; once they are used up, the last such
+	// response is returned to the caller. Transport errors are not retried.
+	// Left at zero, a package-specific default is used.
+	MaxRetries int
+}
@@ -0,0 +1,395 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthRef abstracts the identity backend used to authenticate a StorageClient.
+// Implementations are responsible for obtaining (and refreshing) a storage
+// token and for resolving the object-store endpoint that requests should be
+// issued against.
+type AuthRef interface {
+	// Authenticate performs (or refreshes) authentication and caches the
+	// resulting token and storage endpoint.
+	Authenticate(c *StorageClient) error
+	// Expired reports whether the cached token should be refreshed before
+	// the next request is issued.
+	Expired() bool
+	// Token returns the most recently cached auth token.
+	Token() string
+	// StorageURL returns the object-store endpoint requests should target.
+	StorageURL() string
+}
+
+// tokenLifetime is how long a token is trusted for before this client
+// proactively refreshes it, mirroring the legacy v1 behavior. It always
+// applies to v1 tokens; Keystone v2/v3 tokens use the expiry Keystone
+// returns, and fall back to tokenLifetime only when none could be parsed.
+const tokenLifetime = 25 * time.Minute
+
+// isV1AuthURL reports whether authURL is the legacy Swift TempAuth endpoint,
+// which is the default when no AuthVersion is specified.
+func isV1AuthURL(authURL string) bool {
+	return strings.HasSuffix(authURL, "/auth/v1.0")
+}
+
+// newAuthRef selects the AuthRef implementation for c based on
+// opc.Config.AuthVersion, falling back to sniffing the shape of
+// opc.Config.AuthURL.
+func newAuthRef(c *StorageClient) AuthRef {
+	version := ""
+	authURL := ""
+	if c.client.Config != nil {
+		version = c.client.Config.AuthVersion
+		authURL = c.client.Config.AuthURL
+	}
+
+	switch {
+	case version == "v2":
+		return &v2AuthRef{}
+	case version == "v3":
+		return &v3AuthRef{}
+	case version == "v1" || isV1AuthURL(authURL):
+		return &v1AuthRef{}
+	case strings.Contains(authURL, "/v3"):
+		return &v3AuthRef{}
+	case strings.Contains(authURL, "/v2"):
+		return &v2AuthRef{}
+	default:
+		return &v1AuthRef{}
+	}
+}
+
+// v1AuthRef implements the legacy Swift TempAuth token flow: a GET to
+// /auth/v1.0 with X-Storage-User/X-Storage-Pass headers, returning
+// X-Auth-Token and X-Storage-Url.
+type v1AuthRef struct {
+	mu         sync.Mutex
+	token      string
+	storageURL string
+	issued     time.Time
+}
+
+func (a *v1AuthRef) Authenticate(c *StorageClient) error {
+	req, err := c.client.BuildNonJSONRequest("GET", "/auth/v1.0", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("X-Storage-User", c.getUserName())
+	req.Header.Add("X-Storage-Pass", *c.client.Password)
+
+	resp, err := c.client.ExecuteRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	token := resp.Header.Get(AUTH_HEADER)
+	if token == "" {
+		return fmt.Errorf("storage: no %s header returned from %s", AUTH_HEADER, req.URL)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = token
+	a.storageURL = resp.Header.Get("X-Storage-Url")
+	a.issued = time.Now()
+	return nil
+}
+
+func (a *v1AuthRef) Expired() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token == "" || time.Since(a.issued) > tokenLifetime
+}
+
+func (a *v1AuthRef) Token() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+func (a *v1AuthRef) StorageURL() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.storageURL
+}
+
+// keystoneCatalogEntry is the common shape of a service catalog entry shared
+// by the Keystone v2 and v3 responses we care about.
+type keystoneCatalogEntry struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Endpoints []struct {
+		Region    string `json:"region"`
+		PublicURL string `json:"publicURL"`
+		URL       string `json:"url"`
+		Interface string `json:"interface"`
+	} `json:"endpoints"`
+}
+
+// findObjectStoreURL picks the object-store endpoint for region out of a
+// Keystone service catalog, preferring an exact region match and otherwise
+// falling back to the first object-store endpoint returned.
+func findObjectStoreURL(catalog []keystoneCatalogEntry, region string) (string, error) {
+	var fallback string
+	for _, entry := range catalog {
+		if entry.Type != "object-store" {
+			continue
+		}
+		for _, ep := range entry.Endpoints {
+			url := ep.PublicURL
+			if url == "" {
+				url = ep.URL
+			}
+			if url == "" {
+				continue
+			}
+			if fallback == "" {
+				fallback = url
+			}
+			if region != "" && ep.Region == region {
+				return url, nil
+			}
+		}
+	}
+	if fallback == "" {
+		return "", fmt.Errorf("storage: no object-store endpoint found in service catalog")
+	}
+	return fallback, nil
+}
+
+// v2AuthRef implements Keystone v2 password authentication.
+type v2AuthRef struct {
+	mu         sync.Mutex
+	token      string
+	storageURL string
+	issued     time.Time
+	expires    time.Time
+}
+
+type keystoneV2AuthRequest struct {
+	Auth struct {
+		TenantName          string `json:"tenantName"`
+		PasswordCredentials struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"passwordCredentials"`
+	} `json:"auth"`
+}
+
+type keystoneV2AuthResponse struct {
+	Access struct {
+		Token struct {
+			ID      string `json:"id"`
+			Expires string `json:"expires"`
+		} `json:"token"`
+		ServiceCatalog []keystoneCatalogEntry `json:"serviceCatalog"`
+	} `json:"access"`
+}
+
+func (a *v2AuthRef) Authenticate(c *StorageClient) error {
+	var reqBody keystoneV2AuthRequest
+	reqBody.Auth.TenantName = c.client.Config.TenantName
+	reqBody.Auth.PasswordCredentials.Username = *c.client.UserName
+	reqBody.Auth.PasswordCredentials.Password = *c.client.Password
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.post(c, strings.TrimRight(c.client.Config.AuthURL, "/")+"/tokens", payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var authResp keystoneV2AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return fmt.Errorf("storage: decoding keystone v2 auth response: %s", err)
+	}
+
+	storageURL, err := findObjectStoreURL(authResp.Access.ServiceCatalog, c.client.Config.Region)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = authResp.Access.Token.ID
+	a.storageURL = storageURL
+	a.issued = time.Now()
+	if t, err := time.Parse(time.RFC3339, authResp.Access.Token.Expires); err == nil {
+		a.expires = t
+	}
+	return nil
+}
+
+func (a *v2AuthRef) post(c *StorageClient, url string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.client.ExecuteRequest(req)
+}
+
+func (a *v2AuthRef) Expired() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == "" {
+		return true
+	}
+	if !a.expires.IsZero() {
+		return time.Now().After(a.expires)
+	}
+	return time.Since(a.issued) > tokenLifetime
+}
+
+func (a *v2AuthRef) Token() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+func (a *v2AuthRef) StorageURL() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.storageURL
+}
+
+// v3AuthRef implements Keystone v3 password+domain scoped authentication.
+type v3AuthRef struct {
+	mu         sync.Mutex
+	token      string
+	storageURL string
+	issued     time.Time
+	expires    time.Time
+}
+
+type keystoneV3AuthRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User struct {
+					Name     string `json:"name"`
+					Password string `json:"password"`
+					Domain   struct {
+						Name string `json:"name"`
+					} `json:"domain"`
+				} `json:"user"`
+			} `json:"password"`
+		} `json:"identity"`
+		Scope struct {
+			Project struct {
+				Name   string `json:"name"`
+				Domain struct {
+					Name string `json:"name"`
+				} `json:"domain"`
+			} `json:"project"`
+		} `json:"scope"`
+	} `json:"auth"`
+}
+
+type keystoneV3AuthResponse struct {
+	Token struct {
+		ExpiresAt string                 `json:"expires_at"`
+		Catalog   []keystoneCatalogEntry `json:"catalog"`
+	} `json:"token"`
+}
+
+func (a *v3AuthRef) Authenticate(c *StorageClient) error {
+	var reqBody keystoneV3AuthRequest
+	reqBody.Auth.Identity.Methods = []string{"password"}
+	reqBody.Auth.Identity.Password.User.Name = *c.client.UserName
+	reqBody.Auth.Identity.Password.User.Password = *c.client.Password
+	reqBody.Auth.Identity.Password.User.Domain.Name = c.client.Config.DomainName
+	reqBody.Auth.Scope.Project.Name = c.client.Config.TenantName
+	reqBody.Auth.Scope.Project.Domain.Name = c.client.Config.DomainName
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimRight(c.client.Config.AuthURL, "/")
+	if !strings.HasSuffix(url, "/v3") {
+		url += "/v3"
+	}
+	url += "/auth/tokens"
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.ExecuteRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	subjectToken := resp.Header.Get("X-Subject-Token")
+	if subjectToken == "" {
+		return fmt.Errorf("storage: no X-Subject-Token header returned from %s", url)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var authResp keystoneV3AuthResponse
+	if err := json.Unmarshal(body, &authResp); err != nil {
+		return fmt.Errorf("storage: decoding keystone v3 auth response: %s", err)
+	}
+
+	storageURL, err := findObjectStoreURL(authResp.Token.Catalog, c.client.Config.Region)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = subjectToken
+	a.storageURL = storageURL
+	a.issued = time.Now()
+	if t, err := time.Parse(time.RFC3339, authResp.Token.ExpiresAt); err == nil {
+		a.expires = t
+	}
+	return nil
+}
+
+func (a *v3AuthRef) Expired() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == "" {
+		return true
+	}
+	if !a.expires.IsZero() {
+		return time.Now().After(a.expires)
+	}
+	return time.Since(a.issued) > tokenLifetime
+}
+
+func (a *v3AuthRef) Token() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+func (a *v3AuthRef) StorageURL() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.storageURL
+}
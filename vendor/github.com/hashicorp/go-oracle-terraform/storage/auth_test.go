@@ -0,0 +1,115 @@
+package storage
+
+import "testing"
+
+func TestIsV1AuthURL(t *testing.T) {
+	cases := []struct {
+		authURL string
+		want    bool
+	}{
+		{"https://swift.example.com/auth/v1.0", true},
+		{"https://identity.example.com/v2.0", false},
+		{"https://identity.example.com/v3", false},
+		{"", false},
+	}
+
+	for _, tc := range cases {
+		if got := isV1AuthURL(tc.authURL); got != tc.want {
+			t.Errorf("isV1AuthURL(%q) = %v, want %v", tc.authURL, got, tc.want)
+		}
+	}
+}
+
+func TestFindObjectStoreURLExactRegionMatch(t *testing.T) {
+	catalog := []keystoneCatalogEntry{
+		{
+			Type: "object-store",
+			Name: "swift",
+			Endpoints: []struct {
+				Region    string `json:"region"`
+				PublicURL string `json:"publicURL"`
+				URL       string `json:"url"`
+				Interface string `json:"interface"`
+			}{
+				{Region: "us-east", PublicURL: "https://east.example.com/v1/AUTH_x"},
+				{Region: "us-west", PublicURL: "https://west.example.com/v1/AUTH_x"},
+			},
+		},
+	}
+
+	got, err := findObjectStoreURL(catalog, "us-west")
+	if err != nil {
+		t.Fatalf("findObjectStoreURL returned error: %s", err)
+	}
+	if want := "https://west.example.com/v1/AUTH_x"; got != want {
+		t.Fatalf("findObjectStoreURL(region=us-west) = %q, want %q", got, want)
+	}
+}
+
+func TestFindObjectStoreURLFallsBackWhenRegionUnset(t *testing.T) {
+	catalog := []keystoneCatalogEntry{
+		{
+			Type: "object-store",
+			Endpoints: []struct {
+				Region    string `json:"region"`
+				PublicURL string `json:"publicURL"`
+				URL       string `json:"url"`
+				Interface string `json:"interface"`
+			}{
+				{Region: "us-east", URL: "https://east.example.com/v1/AUTH_x"},
+			},
+		},
+	}
+
+	got, err := findObjectStoreURL(catalog, "")
+	if err != nil {
+		t.Fatalf("findObjectStoreURL returned error: %s", err)
+	}
+	if want := "https://east.example.com/v1/AUTH_x"; got != want {
+		t.Fatalf("findObjectStoreURL(region=\"\") = %q, want %q", got, want)
+	}
+}
+
+func TestFindObjectStoreURLFallsBackWhenRegionNotFound(t *testing.T) {
+	catalog := []keystoneCatalogEntry{
+		{
+			Type: "object-store",
+			Endpoints: []struct {
+				Region    string `json:"region"`
+				PublicURL string `json:"publicURL"`
+				URL       string `json:"url"`
+				Interface string `json:"interface"`
+			}{
+				{Region: "us-east", PublicURL: "https://east.example.com/v1/AUTH_x"},
+			},
+		},
+	}
+
+	got, err := findObjectStoreURL(catalog, "eu-central")
+	if err != nil {
+		t.Fatalf("findObjectStoreURL returned error: %s", err)
+	}
+	if want := "https://east.example.com/v1/AUTH_x"; got != want {
+		t.Fatalf("findObjectStoreURL(region=eu-central) = %q, want fallback %q", got, want)
+	}
+}
+
+func TestFindObjectStoreURLErrorsWhenNoObjectStoreEntry(t *testing.T) {
+	catalog := []keystoneCatalogEntry{
+		{
+			Type: "compute",
+			Endpoints: []struct {
+				Region    string `json:"region"`
+				PublicURL string `json:"publicURL"`
+				URL       string `json:"url"`
+				Interface string `json:"interface"`
+			}{
+				{Region: "us-east", PublicURL: "https://compute.example.com"},
+			},
+		},
+	}
+
+	if _, err := findObjectStoreURL(catalog, "us-east"); err == nil {
+		t.Fatal("findObjectStoreURL did not error for a catalog with no object-store entry")
+	}
+}
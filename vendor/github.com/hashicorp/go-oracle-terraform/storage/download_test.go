@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"crypto/md5"
+	"fmt"
+	"testing"
+)
+
+func TestValidateDownloadHashesPartsInOrder(t *testing.T) {
+	parts := [][]byte{[]byte("hello, "), []byte("world"), []byte("!")}
+
+	sum := md5.New()
+	for _, p := range parts {
+		sum.Write(p)
+	}
+	etag := fmt.Sprintf("%x", sum.Sum(nil))
+
+	if err := validateDownload(etag, parts); err != nil {
+		t.Fatalf("validateDownload with matching ETag returned error: %s", err)
+	}
+
+	// Swapping two parts must not still validate: that would mean the
+	// digest is order-independent, which means it isn't hashing content at
+	// all.
+	scrambled := [][]byte{parts[1], parts[0], parts[2]}
+	if err := validateDownload(etag, scrambled); err == nil {
+		t.Fatal("validateDownload accepted a scrambled part order against the original ETag")
+	}
+}
+
+func TestValidateDownloadRejectsMismatchedETag(t *testing.T) {
+	parts := [][]byte{[]byte("some content")}
+	if err := validateDownload("deadbeef", parts); err == nil {
+		t.Fatal("validateDownload did not reject a mismatched ETag")
+	}
+}
+
+func TestValidateDownloadSkipsMultipartManifestETag(t *testing.T) {
+	parts := [][]byte{[]byte("some content")}
+	// Multipart-manifest ETags are an MD5-of-MD5s and contain a dash
+	// separating the hash from a segment count, e.g. "abc123-4".
+	if err := validateDownload("\"abc123-4\"", parts); err != nil {
+		t.Fatalf("validateDownload should skip multipart-manifest ETags, got error: %s", err)
+	}
+}
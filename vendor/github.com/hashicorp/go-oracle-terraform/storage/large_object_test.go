@@ -0,0 +1,48 @@
+package storage
+
+import "testing"
+
+func TestSegmentDirectoryIsDeterministic(t *testing.T) {
+	first := segmentDirectory("big.iso", "")
+	second := segmentDirectory("big.iso", "")
+
+	if first != second {
+		t.Fatalf("segmentDirectory is not deterministic: %q != %q", first, second)
+	}
+	if first != "big.iso/"+defaultSegmentPrefix {
+		t.Fatalf("segmentDirectory(%q, \"\") = %q, want %q", "big.iso", first, "big.iso/"+defaultSegmentPrefix)
+	}
+}
+
+func TestSegmentDirectoryHonorsOverride(t *testing.T) {
+	got := segmentDirectory("big.iso", "upload-42")
+	want := "big.iso/upload-42"
+	if got != want {
+		t.Fatalf("segmentDirectory(%q, %q) = %q, want %q", "big.iso", "upload-42", got, want)
+	}
+}
+
+func TestSegmentAlreadyUploaded(t *testing.T) {
+	existing := map[string]string{
+		"big.iso/segments/0000000000": "etag-a",
+	}
+
+	cases := []struct {
+		name string
+		path string
+		etag string
+		want bool
+	}{
+		{"matching etag skips re-upload", "big.iso/segments/0000000000", "etag-a", true},
+		{"differing etag forces re-upload", "big.iso/segments/0000000000", "etag-b", false},
+		{"unknown path forces upload", "big.iso/segments/0000000001", "etag-a", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := segmentAlreadyUploaded(existing, tc.path, tc.etag); got != tc.want {
+				t.Errorf("segmentAlreadyUploaded(existing, %q, %q) = %v, want %v", tc.path, tc.etag, got, tc.want)
+			}
+		})
+	}
+}
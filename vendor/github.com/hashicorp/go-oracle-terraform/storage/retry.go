@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxRetries bounds retry attempts when opc.Config.MaxRetries isn't
+// set.
+const defaultMaxRetries = 5
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// executeWithRetry issues req, transparently handling two classes of
+// transient failure:
+//
+//   - 401/403 with a "WWW-Authenticate: Swift" challenge forces a single
+//     re-authentication and replays the request with the refreshed token.
+//   - 429/5xx responses are retried with jittered exponential backoff,
+//     honoring a Retry-After header when the server sends one.
+//
+// body is re-seeked to the start before every replay; it is nil-safe.
+func (c *StorageClient) executeWithRetry(req *http.Request, body io.ReadSeeker) (*http.Response, error) {
+	maxRetries := defaultMaxRetries
+	if c.client.Config != nil && c.client.Config.MaxRetries > 0 {
+		maxRetries = c.client.Config.MaxRetries
+	}
+
+	reauthenticated := false
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.client.ExecuteRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) &&
+			isSwiftAuthChallenge(resp) && !reauthenticated:
+			resp.Body.Close()
+			reauthenticated = true
+
+			if err := c.refreshAuthIfExpired(true); err != nil {
+				return nil, fmt.Errorf("storage: re-authenticating after %d: %s", resp.StatusCode, err)
+			}
+			req.Header.Set(AUTH_HEADER, c.authRef.Token())
+
+			if err := rewind(body); err != nil {
+				return nil, err
+			}
+			continue
+
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			if attempt >= maxRetries {
+				return resp, nil
+			}
+			resp.Body.Close()
+
+			if err := rewind(body); err != nil {
+				return nil, err
+			}
+
+			time.Sleep(retryDelay(attempt, resp.Header.Get("Retry-After")))
+			continue
+
+		default:
+			return resp, nil
+		}
+	}
+}
+
+// isSwiftAuthChallenge reports whether resp is Swift's way of asking for
+// re-authentication, as opposed to a permissions error that a retry can't
+// fix.
+func isSwiftAuthChallenge(resp *http.Response) bool {
+	return strings.Contains(resp.Header.Get("WWW-Authenticate"), "Swift")
+}
+
+// rewind seeks body back to its start so it can be safely resent. A nil body
+// is a no-op; a non-seekable body is a programming error in the caller.
+func rewind(body io.ReadSeeker) error {
+	if body == nil {
+		return nil
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("storage: request body is not seekable, cannot retry: %s", err)
+	}
+	return nil
+}
+
+// retryDelay computes the jittered exponential backoff for attempt,
+// honoring the server's Retry-After value when present.
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt))
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
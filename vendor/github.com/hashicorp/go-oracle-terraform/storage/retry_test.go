@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelayHonorsRetryAfter(t *testing.T) {
+	got := retryDelay(0, "2")
+	want := 2 * time.Second
+	if got != want {
+		t.Fatalf("retryDelay(0, \"2\") = %s, want %s", got, want)
+	}
+}
+
+func TestRetryDelayIgnoresUnparsableRetryAfter(t *testing.T) {
+	got := retryDelay(0, "Wed, 21 Oct 2015 07:28:00 GMT")
+	if got < retryBaseDelay/2 || got > retryBaseDelay {
+		t.Fatalf("retryDelay with unparsable Retry-After = %s, want within [%s, %s]", got, retryBaseDelay/2, retryBaseDelay)
+	}
+}
+
+func TestRetryDelayBacksOffExponentially(t *testing.T) {
+	for attempt := 0; attempt < 6; attempt++ {
+		base := retryBaseDelay * time.Duration(1<<uint(attempt))
+		if base > retryMaxDelay {
+			base = retryMaxDelay
+		}
+
+		got := retryDelay(attempt, "")
+		if got < base/2 || got > base {
+			t.Fatalf("retryDelay(%d, \"\") = %s, want within [%s, %s]", attempt, got, base/2, base)
+		}
+	}
+}
+
+func TestRetryDelayCapsAtMax(t *testing.T) {
+	got := retryDelay(20, "")
+	if got > retryMaxDelay {
+		t.Fatalf("retryDelay(20, \"\") = %s, want capped at %s", got, retryMaxDelay)
+	}
+}
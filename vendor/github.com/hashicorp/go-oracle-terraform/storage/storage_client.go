@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/hashicorp/go-oracle-terraform/client"
 	"github.com/hashicorp/go-oracle-terraform/opc"
@@ -19,9 +20,20 @@ const API_VERSION = "v1"
 
 // Client represents an authenticated compute client, with compute credentials and an api client.
 type StorageClient struct {
-	client      *client.Client
-	authToken   *string
-	tokenIssued time.Time
+	client  *client.Client
+	authRef AuthRef
+
+	// authMu serializes token refreshes against authRef. executeRequestBody
+	// and executeWithRetry both run concurrently from the segment-upload and
+	// ranged-download worker pools, so a bare Expired()-then-Authenticate()
+	// check is racy; authMu turns it into double-checked locking.
+	authMu sync.Mutex
+
+	// accountTempURLKey and containerTempURLKeys cache the TempURL signing
+	// keys set via SetAccountTempURLKey/SetContainerTempURLKey so that
+	// GenerateTempURL doesn't need them passed in on every call.
+	accountTempURLKey    string
+	containerTempURLKeys map[string]string
 }
 
 func NewStorageClient(c *opc.Config) (*StorageClient, error) {
@@ -31,6 +43,7 @@ func NewStorageClient(c *opc.Config) (*StorageClient, error) {
 		return nil, err
 	}
 	sClient.client = opcClient
+	sClient.authRef = newAuthRef(sClient)
 
 	if err := sClient.getAuthenticationToken(); err != nil {
 		return nil, err
@@ -39,13 +52,47 @@ func NewStorageClient(c *opc.Config) (*StorageClient, error) {
 	return sClient, nil
 }
 
+// getAuthenticationToken (re-)authenticates the client against whichever
+// identity backend was selected for it, caching the resulting token and
+// storage endpoint on c.authRef.
+func (c *StorageClient) getAuthenticationToken() error {
+	return c.authRef.Authenticate(c)
+}
+
+// refreshAuthIfExpired re-authenticates c.authRef if it's expired, or if
+// force is true regardless of expiry. It double-checks expiry once authMu is
+// held so concurrent callers that all observed an expired token don't each
+// trigger their own redundant re-authentication.
+func (c *StorageClient) refreshAuthIfExpired(force bool) error {
+	if c.authRef == nil {
+		return nil
+	}
+	if !force && !c.authRef.Expired() {
+		return nil
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if !force && !c.authRef.Expired() {
+		return nil
+	}
+	return c.getAuthenticationToken()
+}
+
 // Execute a request with a nil body
 func (c *StorageClient) executeRequest(method, path string, headers interface{}) (*http.Response, error) {
 	return c.executeRequestBody(method, path, headers, nil)
 }
 
 // Execute a request with a body supplied. The body can be nil for the request.
-// Does not marshal the body into json to create the request
+// Does not marshal the body into json to create the request.
+//
+// body must be an io.ReadSeeker (or nil) because a 401/403 Swift auth
+// challenge triggers a forced re-authentication and a replay of the request,
+// and a 429/5xx response triggers a backoff retry: each replay seeks body back
+// to the start before resending it. If that Seek fails, the request is not
+// resent and the error is returned to the caller.
 func (c *StorageClient) executeRequestBody(method, path string, headers interface{}, body io.ReadSeeker) (*http.Response, error) {
 	req, err := c.client.BuildNonJSONRequest(method, path, body)
 	if err != nil {
@@ -70,20 +117,23 @@ func (c *StorageClient) executeRequestBody(method, path string, headers interfac
 	}
 
 	// If we have an authentication token, let's authenticate, refreshing cookie if need be
-	if c.authToken != nil {
-		if time.Since(c.tokenIssued).Minutes() > 25 {
-			if err := c.getAuthenticationToken(); err != nil {
-				return nil, err
-			}
+	if c.authRef != nil {
+		if err := c.refreshAuthIfExpired(false); err != nil {
+			return nil, err
+		}
+		req.Header.Add(AUTH_HEADER, c.authRef.Token())
+
+		// Keystone v2/v3 auth resolves a region-specific object-store
+		// endpoint from the service catalog that can differ from
+		// c.client.APIEndpoint (the endpoint BuildNonJSONRequest used to
+		// build req); route the request there instead. The legacy v1
+		// flow's StorageURL matches APIEndpoint, so this is a no-op for it.
+		if err := retargetToStorageURL(req, c.authRef.StorageURL()); err != nil {
+			return nil, err
 		}
-		req.Header.Add(AUTH_HEADER, *c.authToken)
 	}
 
-	resp, err := c.client.ExecuteRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+	return c.executeWithRetry(req, body)
 }
 
 func (c *StorageClient) getUserName() string {
@@ -94,6 +144,14 @@ func (c *StorageClient) getAccount() string {
 	return fmt.Sprintf(STR_ACCOUNT, *c.client.IdentityDomain)
 }
 
+// getAccountPath returns the fully-qualified path of the account root
+// itself, e.g. /v1/{account}. Unlike getQualifiedName, which treats an empty
+// name as "no object" and returns "", this is for endpoints that operate on
+// the account as a resource (e.g. setting account-level metadata).
+func (c *StorageClient) getAccountPath() string {
+	return fmt.Sprintf("%s%s", API_VERSION, c.getAccount())
+}
+
 // GetQualifiedName returns the fully-qualified name of a storage object, e.g. /v1/{account}/{name}
 func (c *StorageClient) getQualifiedName(name string) string {
 	if name == "" {
@@ -118,6 +176,26 @@ func (c *StorageClient) getUnqualifiedName(name string) string {
 	return strings.Join(nameParts[len(nameParts)-1:], "/")
 }
 
+// retargetToStorageURL repoints req's scheme and host at storageURL, leaving
+// its path, query, and everything else untouched. storageURL is the
+// AuthRef's resolved object-store endpoint; an empty value (as with a
+// not-yet-authenticated AuthRef) is a no-op.
+func retargetToStorageURL(req *http.Request, storageURL string) error {
+	if storageURL == "" {
+		return nil
+	}
+
+	target, err := url.Parse(storageURL)
+	if err != nil {
+		return fmt.Errorf("storage: parsing storage URL %q: %s", storageURL, err)
+	}
+
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	req.Host = target.Host
+	return nil
+}
+
 func (c *StorageClient) unqualify(names ...*string) {
 	for _, name := range names {
 		*name = c.getUnqualifiedName(*name)
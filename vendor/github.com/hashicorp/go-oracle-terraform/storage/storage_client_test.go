@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRetargetToStorageURLRepointsSchemeAndHost(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://original.example.com/v1/AUTH_x/container/object", nil)
+	if err != nil {
+		t.Fatalf("building request: %s", err)
+	}
+
+	if err := retargetToStorageURL(req, "http://region2.example.com/ignored-path"); err != nil {
+		t.Fatalf("retargetToStorageURL returned error: %s", err)
+	}
+
+	if req.URL.Scheme != "http" || req.URL.Host != "region2.example.com" {
+		t.Fatalf("retargetToStorageURL did not repoint scheme/host: got %s://%s", req.URL.Scheme, req.URL.Host)
+	}
+	if req.URL.Path != "/v1/AUTH_x/container/object" {
+		t.Fatalf("retargetToStorageURL changed the request path: got %q", req.URL.Path)
+	}
+}
+
+func TestRetargetToStorageURLIsNoOpWhenEmpty(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://original.example.com/v1/AUTH_x/container/object", nil)
+	if err != nil {
+		t.Fatalf("building request: %s", err)
+	}
+
+	if err := retargetToStorageURL(req, ""); err != nil {
+		t.Fatalf("retargetToStorageURL returned error: %s", err)
+	}
+	if req.URL.Scheme != "https" || req.URL.Host != "original.example.com" {
+		t.Fatalf("retargetToStorageURL modified the request with an empty storageURL: got %s://%s", req.URL.Scheme, req.URL.Host)
+	}
+}
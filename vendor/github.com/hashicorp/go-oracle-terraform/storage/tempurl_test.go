@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-oracle-terraform/client"
+)
+
+func TestSignTempURLMatchesKnownFixture(t *testing.T) {
+	// HMAC-SHA1 of "GET\n1400000000\n/v1/AUTH_test/container/object" keyed
+	// by "testsecret", verified independently against Python's hmac module.
+	got, err := signTempURL(TempURLDigestSHA1, "testsecret", "GET", "/v1/AUTH_test/container/object", 1400000000)
+	if err != nil {
+		t.Fatalf("signTempURL returned error: %s", err)
+	}
+	if want := "b3232226e03d18a2f346842c9d42635c97c8bc16"; got != want {
+		t.Fatalf("signTempURL(...) = %q, want %q", got, want)
+	}
+}
+
+func TestSignTempURLDiffersByDigest(t *testing.T) {
+	sha1Sig, err := signTempURL(TempURLDigestSHA1, "testsecret", "GET", "/v1/AUTH_test/container/object", 1400000000)
+	if err != nil {
+		t.Fatalf("signTempURL(sha1) returned error: %s", err)
+	}
+	sha256Sig, err := signTempURL(TempURLDigestSHA256, "testsecret", "GET", "/v1/AUTH_test/container/object", 1400000000)
+	if err != nil {
+		t.Fatalf("signTempURL(sha256) returned error: %s", err)
+	}
+	if sha1Sig == sha256Sig {
+		t.Fatal("signTempURL produced identical signatures for different digests")
+	}
+}
+
+func newTestStorageClient(domain string) *StorageClient {
+	return &StorageClient{client: &client.Client{IdentityDomain: &domain}}
+}
+
+func TestContainerOfUnqualifiedName(t *testing.T) {
+	c := newTestStorageClient("test")
+	if got, want := c.containerOf("container/object"), "container"; got != want {
+		t.Fatalf("containerOf(%q) = %q, want %q", "container/object", got, want)
+	}
+}
+
+func TestContainerOfQualifiedName(t *testing.T) {
+	c := newTestStorageClient("test")
+	qualified := c.getQualifiedName("container/nested/object")
+	if got, want := c.containerOf(qualified), "container"; got != want {
+		t.Fatalf("containerOf(%q) = %q, want %q", qualified, got, want)
+	}
+}